@@ -15,10 +15,12 @@
 package burner
 
 import (
+	"strings"
 	"time"
 
 	"github.com/cloud-bulldozer/go-commons/indexers"
 	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -44,12 +46,14 @@ const jobSummaryMetric = "jobSummary"
 // indexMetadataInfo Generates and indexes a document with metadata information of the passed job
 func IndexJobSummary(jobSummaries []JobSummary, indexer indexers.Indexer) {
 	log.Info("Indexing job summaries")
+	reporters := measurements.CollectSLOReporters()
 	var jobSummariesInt []interface{}
 	indexingOpts := indexers.IndexingOpts{
 		MetricName: jobSummaryMetric,
 	}
-	for _, summary := range jobSummaries {
-		jobSummariesInt = append(jobSummariesInt, summary)
+	for i := range jobSummaries {
+		MergeSLOResults(&jobSummaries[i], reporters)
+		jobSummariesInt = append(jobSummariesInt, jobSummaries[i])
 	}
 	resp, err := indexer.Index(jobSummariesInt, indexingOpts)
 	if err != nil {
@@ -58,3 +62,31 @@ func IndexJobSummary(jobSummaries []JobSummary, indexer indexers.Indexer) {
 		log.Info(resp)
 	}
 }
+
+// MergeSLOResults folds the SLO verdict each measurement that implements measurements.SLOReporter
+// (serviceLatency, ingressLatency, ...) recorded for summary.JobConfig.Name into the job's summary, so
+// a single measurement's declared SLOs (types.Measurement.SLOs) can fail the job without each
+// measurement having to know about JobSummary itself. Measurements are package-level singletons reused
+// across every Job in a run, so reporters are looked up by job name rather than assumed to describe
+// summary alone; a reporter that never ran for this job (ok == false) is skipped. Passed/ExecutionErrors
+// may already carry a verdict set elsewhere (e.g. a non-SLO failure), so an SLO pass never sets Passed
+// back to true, and any existing ExecutionErrors is kept rather than overwritten.
+func MergeSLOResults(summary *JobSummary, reporters []measurements.SLOReporter) {
+	var executionErrors []string
+	if summary.ExecutionErrors != "" {
+		executionErrors = append(executionErrors, summary.ExecutionErrors)
+	}
+	for _, reporter := range reporters {
+		passed, errs, ok := reporter.SLOStatusForJob(summary.JobConfig.Name)
+		if !ok {
+			continue
+		}
+		if !passed {
+			summary.Passed = false
+		}
+		if errs != "" {
+			executionErrors = append(executionErrors, errs)
+		}
+	}
+	summary.ExecutionErrors = strings.Join(executionErrors, "; ")
+}
@@ -0,0 +1,354 @@
+// Copyright 2023 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/metrics"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/measurements/util"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	lnetworkingv1 "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	ingressLatencyMetric               = "ingressLatencyMeasurement"
+	ingressLatencyQuantilesMeasurement = "ingressLatencyQuantilesMeasurement"
+)
+
+// routeGVR identifies the OpenShift Route resource. It's watched through the dynamic client so that
+// kube-burner core doesn't need to vendor github.com/openshift/api just to support this one measurement.
+var routeGVR = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+type ingressLatency struct {
+	config         types.Measurement
+	ingressWatcher *metrics.Watcher
+	ingressLister  lnetworkingv1.IngressLister
+	routeInformer  cache.SharedIndexInformer
+	routeStopCh    chan struct{}
+
+	metrics          map[string]ingressMetric
+	latencyQuantiles []interface{}
+	normLatencies    []interface{}
+	metricLock       sync.RWMutex
+	sloResults       sloResults
+}
+
+type ingressMetric struct {
+	Timestamp           time.Time     `json:"timestamp"`
+	AdmittedLatency     time.Duration `json:"admitted"`
+	DNSLatency          time.Duration `json:"dnsResolution,omitempty"`
+	TLSHandshakeLatency time.Duration `json:"tlsHandshake,omitempty"`
+	ReadyLatency        time.Duration `json:"ready"`
+	MetricName          string        `json:"metricName"`
+	JobConfig           config.Job    `json:"jobConfig"`
+	UUID                string        `json:"uuid"`
+	Namespace           string        `json:"namespace"`
+	Name                string        `json:"ingress"`
+	Kind                string        `json:"kind"`
+	Host                string        `json:"host"`
+	Metadata            interface{}   `json:"metadata,omitempty"`
+}
+
+func init() {
+	measurementMap["ingressLatency"] = &ingressLatency{
+		metrics: map[string]ingressMetric{},
+	}
+}
+
+// waitForHost blocks until the object is admitted and returns the externally reachable host.
+type waitForHostFunc func() (string, error)
+
+func (i *ingressLatency) handleCreateIngress(obj interface{}) {
+	ingress := obj.(*networkingv1.Ingress)
+	log.Debugf("New ingress created: %v/%v", ingress.Namespace, ingress.Name)
+	go i.probe(string(ingress.UID), "Ingress", ingress.Namespace, ingress.Name, ingress.CreationTimestamp.Time.UTC(), func() (string, error) {
+		var host string
+		err := wait.PollUntilContextCancel(context.TODO(), 100*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+			current, err := i.ingressLister.Ingresses(ingress.Namespace).Get(ingress.Name)
+			if err != nil {
+				return false, nil
+			}
+			if len(current.Status.LoadBalancer.Ingress) == 0 {
+				return false, nil
+			}
+			switch {
+			case len(current.Spec.Rules) > 0:
+				host = current.Spec.Rules[0].Host
+			case current.Status.LoadBalancer.Ingress[0].Hostname != "":
+				host = current.Status.LoadBalancer.Ingress[0].Hostname
+			default:
+				host = current.Status.LoadBalancer.Ingress[0].IP
+			}
+			return true, nil
+		})
+		return host, err
+	})
+}
+
+func (i *ingressLatency) handleCreateRoute(obj interface{}) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	namespace, name := u.GetNamespace(), u.GetName()
+	log.Debugf("New route created: %v/%v", namespace, name)
+	go i.probe(string(u.GetUID()), "Route", namespace, name, u.GetCreationTimestamp().Time.UTC(), func() (string, error) {
+		var host string
+		err := wait.PollUntilContextCancel(context.TODO(), 100*time.Millisecond, true, func(ctx context.Context) (bool, error) {
+			current, err := factory.dynamicClient.Resource(routeGVR).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil || !routeAdmitted(current) {
+				return false, nil
+			}
+			host, _, _ = unstructured.NestedString(current.Object, "spec", "host")
+			return host != "", nil
+		})
+		return host, err
+	})
+}
+
+// routeAdmitted reports whether any ingress point of the Route reports an Admitted=True condition.
+func routeAdmitted(route *unstructured.Unstructured) bool {
+	ingressPoints, found, _ := unstructured.NestedSlice(route.Object, "status", "ingress")
+	if !found {
+		return false
+	}
+	for _, ip := range ingressPoints {
+		entry, ok := ip.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditions, _, _ := unstructured.NestedSlice(entry, "conditions")
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if ok && condition["type"] == "Admitted" && condition["status"] == "True" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// probe waits for admission and then exercises the in-cluster checker pod against the resulting host,
+// recording admitted/DNS/TLS-handshake/ready latencies for the object.
+func (i *ingressLatency) probe(uid, kind, namespace, name string, creationTs time.Time, waitForHost waitForHostFunc) {
+	now := time.Now()
+	host, err := waitForHost()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	admittedLatency := time.Since(now)
+	log.Debugf("%s %v/%v admitted, host: %v", kind, namespace, name, host)
+	checker, err := util.NewSvcLatencyChecker(*factory.clientSet, *factory.restConfig)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	readyTs := time.Now()
+	dnsLatency, tlsLatency, err := checker.ProbeHTTPDetailed(host, i.config.ProbePath, i.config.ProbeScheme, i.config.ProbeExpectedStatus, i.config.IngressTimeout)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	readyLatency := time.Since(readyTs)
+	i.metricLock.Lock()
+	i.metrics[uid] = ingressMetric{
+		Timestamp:           creationTs,
+		MetricName:          ingressLatencyMetric,
+		JobConfig:           *factory.jobConfig,
+		UUID:                globalCfg.UUID,
+		Namespace:           namespace,
+		Name:                name,
+		Kind:                kind,
+		Host:                host,
+		Metadata:            factory.metadata,
+		AdmittedLatency:     admittedLatency,
+		DNSLatency:          dnsLatency,
+		TLSHandshakeLatency: tlsLatency,
+		ReadyLatency:        readyLatency,
+	}
+	i.metricLock.Unlock()
+}
+
+func (i *ingressLatency) setConfig(cfg types.Measurement) error {
+	i.config = cfg
+	if i.config.IngressTimeout == 0 {
+		log.Fatal("ingressTimeout not set in ingress latency measurement")
+	}
+	return nil
+}
+
+// start ingress/route latency measurement
+func (i *ingressLatency) start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	if err := deployAssets(); err != nil {
+		log.Fatal(err)
+		return err
+	}
+	log.Infof("Creating ingress latency watcher for %s", factory.jobConfig.Name)
+	i.ingressWatcher = metrics.NewWatcher(
+		factory.clientSet.NetworkingV1().RESTClient().(*rest.RESTClient),
+		"ingressWatcher",
+		"ingresses",
+		corev1.NamespaceAll,
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = fmt.Sprintf("kube-burner-runid=%v", globalCfg.RUNID)
+		},
+		cache.Indexers{},
+	)
+	i.ingressWatcher.Informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: i.handleCreateIngress,
+	})
+	i.ingressLister = lnetworkingv1.NewIngressLister(i.ingressWatcher.Informer.GetIndexer())
+	if err := i.ingressWatcher.StartAndCacheSync(); err != nil {
+		return fmt.Errorf("Ingress Latency measurement error: %s", err)
+	}
+	// Route is an OpenShift-only resource; watched directly through the dynamic client since kube-burner
+	// core has no typed client for it. A missing CRD on vanilla Kubernetes is not fatal.
+	i.routeStopCh = make(chan struct{})
+	i.routeInformer = cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				options.LabelSelector = fmt.Sprintf("kube-burner-runid=%v", globalCfg.RUNID)
+				return factory.dynamicClient.Resource(routeGVR).Namespace(corev1.NamespaceAll).List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				options.LabelSelector = fmt.Sprintf("kube-burner-runid=%v", globalCfg.RUNID)
+				return factory.dynamicClient.Resource(routeGVR).Namespace(corev1.NamespaceAll).Watch(context.TODO(), options)
+			},
+		},
+		&unstructured.Unstructured{},
+		0,
+		cache.Indexers{},
+	)
+	i.routeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: i.handleCreateRoute,
+	})
+	go i.routeInformer.Run(i.routeStopCh)
+	syncCtx, cancelSync := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelSync()
+	if !cache.WaitForCacheSync(syncCtx.Done(), i.routeInformer.HasSynced) {
+		log.Warn("Route watcher cache did not sync, Route objects won't be measured in this run")
+	}
+	return nil
+}
+
+func (i *ingressLatency) stop() error {
+	i.ingressWatcher.StopWatcher()
+	if i.routeStopCh != nil {
+		close(i.routeStopCh)
+	}
+	i.normalizeMetrics()
+	passed, executionErrors := evaluateSLOs(i.config.SLOs, i.latencyQuantiles)
+	i.sloResults.record(factory.jobConfig.Name, passed, executionErrors)
+	if !passed {
+		log.Warnf("Ingress latency SLOs not met for job %s: %s", factory.jobConfig.Name, executionErrors)
+	}
+	if globalCfg.IndexerConfig.Type != "" {
+		if factory.jobConfig.SkipIndexing {
+			log.Infof("Skipping ingress latency data indexing in job: %s", factory.jobConfig.Name)
+		} else {
+			log.Infof("Indexing ingress latency data for job: %s", factory.jobConfig.Name)
+			i.index()
+		}
+	}
+	for _, q := range i.latencyQuantiles {
+		pq := q.(metrics.LatencyQuantiles)
+		// Divide nanoseconds by 1e6 to get milliseconds
+		log.Infof("%s: %s 50th: %dms 99th: %dms max: %dms avg: %dms", factory.jobConfig.Name, pq.QuantileName, pq.P50/1e6, pq.P99/1e6, pq.Max/1e6, pq.Avg/1e6)
+	}
+	return nil
+}
+
+func (i *ingressLatency) normalizeMetrics() {
+	var admittedLatencies, dnsLatencies, tlsLatencies, readyLatencies []float64
+	for _, metric := range i.metrics {
+		admittedLatencies = append(admittedLatencies, float64(metric.AdmittedLatency))
+		readyLatencies = append(readyLatencies, float64(metric.ReadyLatency))
+		if metric.DNSLatency != 0 {
+			dnsLatencies = append(dnsLatencies, float64(metric.DNSLatency))
+		}
+		if metric.TLSHandshakeLatency != 0 {
+			tlsLatencies = append(tlsLatencies, float64(metric.TLSHandshakeLatency))
+		}
+		i.normLatencies = append(i.normLatencies, metric)
+	}
+	calcSummary := func(name string, inputLatencies []float64) metrics.LatencyQuantiles {
+		latencySummary := metrics.NewLatencySummary(inputLatencies, name)
+		latencySummary.UUID = globalCfg.UUID
+		latencySummary.JobConfig = *factory.jobConfig
+		latencySummary.Timestamp = time.Now().UTC()
+		latencySummary.Metadata = factory.metadata
+		latencySummary.MetricName = ingressLatencyQuantilesMeasurement
+		return latencySummary
+	}
+	i.latencyQuantiles = []interface{}{calcSummary("Admitted", admittedLatencies), calcSummary("Ready", readyLatencies)}
+	if len(dnsLatencies) > 0 {
+		i.latencyQuantiles = append(i.latencyQuantiles, calcSummary("DNSResolution", dnsLatencies))
+	}
+	if len(tlsLatencies) > 0 {
+		i.latencyQuantiles = append(i.latencyQuantiles, calcSummary("TLSHandshake", tlsLatencies))
+	}
+}
+
+func (i *ingressLatency) index() {
+	metricMap := map[string][]interface{}{
+		ingressLatencyMetric:               i.normLatencies,
+		ingressLatencyQuantilesMeasurement: i.latencyQuantiles,
+	}
+	if i.config.ServiceLatencyMetrics == types.Quantiles {
+		delete(metricMap, ingressLatencyMetric)
+	}
+	for metricName, documents := range metricMap {
+		indexingOpts := indexers.IndexingOpts{
+			MetricName: fmt.Sprintf("%s-%s", metricName, factory.jobConfig.Name),
+		}
+		log.Debugf("Indexing [%d] documents: %s", len(documents), metricName)
+		resp, err := (*factory.indexer).Index(documents, indexingOpts)
+		if err != nil {
+			log.Error(err.Error())
+		} else {
+			log.Info(resp)
+		}
+	}
+}
+
+func (i *ingressLatency) collect(measurementWg *sync.WaitGroup) {
+	defer measurementWg.Done()
+}
+
+// SLOStatusForJob reports whether this measurement's SLOs (i.config.SLOs) passed for jobName, along
+// with a description of any violation, for merging into that job's JobSummary. ok is false if stop()
+// never ran this measurement for jobName.
+func (i *ingressLatency) SLOStatusForJob(jobName string) (bool, string, bool) {
+	return i.sloResults.forJob(jobName)
+}
@@ -17,6 +17,7 @@ package measurements
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -40,6 +41,24 @@ const (
 	svcLatencyQuantilesMeasurement = "svcLatencyQuantilesMeasurement"
 )
 
+// ProbeMode selects the protocol the service latency checker uses to reach a Service.
+type ProbeMode string
+
+const (
+	ProbeModeTCP   ProbeMode = "tcp"
+	ProbeModeUDP   ProbeMode = "udp"
+	ProbeModeHTTP  ProbeMode = "http"
+	ProbeModeHTTPS ProbeMode = "https"
+)
+
+// protocol returns the corev1.Protocol a Service port must expose for this probe mode to apply to it.
+func (p ProbeMode) protocol() corev1.Protocol {
+	if p == ProbeModeUDP {
+		return corev1.ProtocolUDP
+	}
+	return corev1.ProtocolTCP
+}
+
 type serviceLatency struct {
 	config           types.Measurement
 	svcWatcher       *metrics.Watcher
@@ -50,6 +69,7 @@ type serviceLatency struct {
 	latencyQuantiles []interface{}
 	normLatencies    []interface{}
 	metricLock       sync.RWMutex
+	sloResults       sloResults
 }
 
 type svcMetric struct {
@@ -63,6 +83,7 @@ type svcMetric struct {
 	Name              string             `json:"service"`
 	Metadata          interface{}        `json:"metadata,omitempty"`
 	ServiceType       corev1.ServiceType `json:"type"`
+	ProbeMode         ProbeMode          `json:"probeMode"`
 }
 
 func init() {
@@ -91,10 +112,19 @@ func deployAssets() error {
 	return nil
 }
 
+// probeMode returns the configured probe mode for this measurement, defaulting to a plain TCP dial.
+func (s *serviceLatency) probeMode() ProbeMode {
+	if s.config.ProbeMode == "" {
+		return ProbeModeTCP
+	}
+	return ProbeMode(s.config.ProbeMode)
+}
+
 func (s *serviceLatency) handleCreateSvc(obj interface{}) {
 	// TODO Magic annotation to skip service
 	svc := obj.(*corev1.Service)
 	log.Debugf("New service created: %v/%v", svc.Namespace, svc.Name)
+	probeMode := s.probeMode()
 	go func(svc *corev1.Service) {
 		var ips []string
 		var port int32
@@ -117,7 +147,7 @@ func (s *serviceLatency) handleCreateSvc(obj interface{}) {
 			log.Error(err)
 		}
 		for _, specPort := range svc.Spec.Ports {
-			if specPort.Protocol == corev1.ProtocolTCP { // Support TCP protocol
+			if specPort.Protocol == probeMode.protocol() {
 				switch svc.Spec.Type {
 				case corev1.ServiceTypeClusterIP:
 					ips = svc.Spec.ClusterIPs
@@ -139,7 +169,14 @@ func (s *serviceLatency) handleCreateSvc(obj interface{}) {
 					return
 				}
 				for _, ip := range ips {
-					err = svcLatencyChecker.Ping(ip, port, s.config.ServiceTimeout)
+					switch probeMode {
+					case ProbeModeUDP:
+						err = svcLatencyChecker.PingUDP(ip, port, s.config.ServiceTimeout)
+					case ProbeModeHTTP, ProbeModeHTTPS:
+						err = svcLatencyChecker.PingHTTP(ip, port, string(probeMode), s.config.ProbePath, s.config.ProbeExpectedStatus, s.config.ServiceTimeout)
+					default:
+						err = svcLatencyChecker.Ping(ip, port, s.config.ServiceTimeout)
+					}
 					if err != nil {
 						log.Error(err)
 						return
@@ -161,6 +198,7 @@ func (s *serviceLatency) handleCreateSvc(obj interface{}) {
 			UUID:              globalCfg.UUID,
 			Metadata:          factory.metadata,
 			IPAssignedLatency: ipAssignedLatency,
+			ProbeMode:         probeMode,
 		}
 		s.metricLock.Unlock()
 	}(svc)
@@ -222,6 +260,11 @@ func (s *serviceLatency) stop() error {
 	// TODO wait for namespace to be deleted
 	factory.clientSet.CoreV1().Namespaces().Delete(context.TODO(), types.SvcLatencyNs.Name, metav1.DeleteOptions{})
 	s.normalizeMetrics()
+	passed, executionErrors := evaluateSLOs(s.config.SLOs, s.latencyQuantiles)
+	s.sloResults.record(factory.jobConfig.Name, passed, executionErrors)
+	if !passed {
+		log.Warnf("Service latency SLOs not met for job %s: %s", factory.jobConfig.Name, executionErrors)
+	}
 	if globalCfg.IndexerConfig.Type != "" {
 		if factory.jobConfig.SkipIndexing {
 			log.Infof("Skipping service latency data indexing in job: %s", factory.jobConfig.Name)
@@ -239,10 +282,10 @@ func (s *serviceLatency) stop() error {
 }
 
 func (s *serviceLatency) normalizeMetrics() {
-	var latencies []float64
+	latenciesByMode := map[ProbeMode][]float64{}
 	var ipAssignedLatencies []float64
 	for _, metric := range s.metrics {
-		latencies = append(latencies, float64(metric.ReadyLatency))
+		latenciesByMode[metric.ProbeMode] = append(latenciesByMode[metric.ProbeMode], float64(metric.ReadyLatency))
 		s.normLatencies = append(s.normLatencies, metric)
 		if metric.IPAssignedLatency != 0 {
 			ipAssignedLatencies = append(ipAssignedLatencies, float64(metric.IPAssignedLatency))
@@ -257,7 +300,21 @@ func (s *serviceLatency) normalizeMetrics() {
 		latencySummary.MetricName = svcLatencyQuantilesMeasurement
 		return latencySummary
 	}
-	s.latencyQuantiles = []interface{}{calcSummary("Ready", latencies)}
+	// Compute a separate quantile summary per probe mode, e.g. "Ready" (tcp), "Ready-http", "Ready-udp",
+	// so L4 and L7 latencies are never blended together.
+	var modes []string
+	for mode := range latenciesByMode {
+		modes = append(modes, string(mode))
+	}
+	sort.Strings(modes)
+	s.latencyQuantiles = nil
+	for _, mode := range modes {
+		quantileName := "Ready"
+		if ProbeMode(mode) != ProbeModeTCP {
+			quantileName = fmt.Sprintf("Ready-%s", mode)
+		}
+		s.latencyQuantiles = append(s.latencyQuantiles, calcSummary(quantileName, latenciesByMode[ProbeMode(mode)]))
+	}
 	if len(ipAssignedLatencies) > 0 {
 		s.latencyQuantiles = append(s.latencyQuantiles, calcSummary("IPAssigned", ipAssignedLatencies))
 	}
@@ -317,4 +374,11 @@ func (s *serviceLatency) waitForIngress(svc *corev1.Service) error {
 
 func (s *serviceLatency) collect(measurementWg *sync.WaitGroup) {
 	defer measurementWg.Done()
+}
+
+// SLOStatusForJob reports whether this measurement's SLOs (s.config.SLOs) passed for jobName, along
+// with a description of any violation, for merging into that job's JobSummary. ok is false if stop()
+// never ran this measurement for jobName.
+func (s *serviceLatency) SLOStatusForJob(jobName string) (bool, string, bool) {
+	return s.sloResults.forJob(jobName)
 }
\ No newline at end of file
@@ -0,0 +1,137 @@
+// Copyright 2024 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/measurements/metrics"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+)
+
+// SLOReporter is implemented by measurements that evaluate pass/fail SLOs against their own collected
+// metrics (serviceLatency and ingressLatency), so the job orchestrator can fold the
+// result into that job's JobSummary. Measurements are registered once in measurementMap and reused
+// across every Job in a run, so a verdict is looked up by jobName rather than returned unconditionally
+// - ok is false when the reporter never ran stop() for that job.
+type SLOReporter interface {
+	SLOStatusForJob(jobName string) (passed bool, executionErrors string, ok bool)
+}
+
+// sloResults records, per job name, the SLO verdict a measurement computed the last time its stop()
+// ran for that job. A measurement instance is a package-level singleton reused across every Job in a
+// run, so without this per-job bookkeeping a later job's verdict would silently overwrite an earlier
+// job's in a single shared field.
+type sloResults struct {
+	mu      sync.Mutex
+	results map[string]sloResult
+}
+
+type sloResult struct {
+	passed          bool
+	executionErrors string
+}
+
+// record stores the SLO verdict computed for jobName, overwriting any previous verdict recorded for
+// that same job name (e.g. a job that reruns its measurement across iterations of the same job config).
+func (r *sloResults) record(jobName string, passed bool, executionErrors string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.results == nil {
+		r.results = map[string]sloResult{}
+	}
+	r.results[jobName] = sloResult{passed: passed, executionErrors: executionErrors}
+}
+
+// forJob returns the verdict recorded for jobName, and false if this measurement never recorded one.
+func (r *sloResults) forJob(jobName string) (passed bool, executionErrors string, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res, ok := r.results[jobName]
+	return res.passed, res.executionErrors, ok
+}
+
+// evaluateSLOs checks each configured types.SLOTarget against a set of quantile summaries, as produced
+// by a measurement's normalizeMetrics, and returns whether they all passed along with a
+// semicolon-separated description of any violation. Shared by every measurement that declares SLOs
+// through types.Measurement.SLOs, so the config shape and evaluation rules stay identical across them.
+func evaluateSLOs(slos []types.SLOTarget, latencyQuantiles []interface{}) (bool, string) {
+	if len(slos) == 0 {
+		return true, ""
+	}
+	var violations []string
+	for _, slo := range slos {
+		summary, ok := quantileSummary(latencyQuantiles, slo.MetricName)
+		if !ok {
+			violations = append(violations, fmt.Sprintf("SLO error: no %q latency summary was collected", slo.MetricName))
+			continue
+		}
+		observedMs, ok := quantileValueMs(summary, slo.Quantile)
+		if !ok {
+			violations = append(violations, fmt.Sprintf("SLO error: unknown quantile %q for metric %q", slo.Quantile, slo.MetricName))
+			continue
+		}
+		if observedMs > slo.ThresholdMs {
+			violations = append(violations, fmt.Sprintf("SLO violated: %s %s latency %dms exceeds threshold %dms", slo.MetricName, slo.Quantile, observedMs, slo.ThresholdMs))
+		}
+	}
+	if len(violations) > 0 {
+		return false, strings.Join(violations, "; ")
+	}
+	return true, ""
+}
+
+// CollectSLOReporters returns every registered measurement that implements SLOReporter, so a caller
+// building a JobSummary can fold each measurement's SLO verdict into it without hardcoding which
+// measurements support SLOs.
+func CollectSLOReporters() []SLOReporter {
+	var reporters []SLOReporter
+	for _, m := range measurementMap {
+		if reporter, ok := m.(SLOReporter); ok {
+			reporters = append(reporters, reporter)
+		}
+	}
+	return reporters
+}
+
+func quantileSummary(latencyQuantiles []interface{}, metricName string) (metrics.LatencyQuantiles, bool) {
+	for _, q := range latencyQuantiles {
+		pq := q.(metrics.LatencyQuantiles)
+		if pq.QuantileName == metricName {
+			return pq, true
+		}
+	}
+	return metrics.LatencyQuantiles{}, false
+}
+
+func quantileValueMs(pq metrics.LatencyQuantiles, quantile string) (int, bool) {
+	var d time.Duration
+	switch quantile {
+	case "P50":
+		d = pq.P50
+	case "P99":
+		d = pq.P99
+	case "Max":
+		d = pq.Max
+	case "Avg":
+		d = pq.Avg
+	default:
+		return 0, false
+	}
+	return int(d / time.Millisecond), true
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceLatencyMetricsProfile selects which documents serviceLatency/ingressLatency index.
+type ServiceLatencyMetricsProfile string
+
+// Quantiles restricts indexing to the per-quantile summary documents, skipping the raw per-object
+// latency documents.
+const Quantiles ServiceLatencyMetricsProfile = "quantiles"
+
+// Measurement is the user-facing configuration of a single measurement, as declared under a job's
+// "measurements" list.
+type Measurement struct {
+	// ServiceTimeout bounds how long serviceLatency waits for a Service's ports to answer probes.
+	ServiceTimeout time.Duration `json:"svcTimeout" yaml:"svcTimeout"`
+	// IngressTimeout bounds how long ingressLatency waits for an Ingress/Route host to become ready.
+	IngressTimeout time.Duration `json:"ingressTimeout" yaml:"ingressTimeout"`
+	// ServiceLatencyMetrics controls which documents are indexed for serviceLatency/ingressLatency.
+	ServiceLatencyMetrics ServiceLatencyMetricsProfile `json:"metricsProfile" yaml:"metricsProfile"`
+	// ProbeMode selects the protocol serviceLatency uses to reach a Service: tcp (default), udp, http or https.
+	ProbeMode string `json:"probeMode" yaml:"probeMode"`
+	// ProbeScheme selects http or https for ingressLatency readiness probes.
+	ProbeScheme string `json:"probeScheme" yaml:"probeScheme"`
+	// ProbePath is the HTTP(S) path requested by http/https probe modes.
+	ProbePath string `json:"probePath" yaml:"probePath"`
+	// ProbeExpectedStatus is the HTTP status code a probe must observe for its target to be considered ready.
+	ProbeExpectedStatus int `json:"probeExpectedStatus" yaml:"probeExpectedStatus"`
+	// SLOs declares pass/fail latency thresholds evaluated once the measurement stops.
+	SLOs []SLOTarget `json:"slos" yaml:"slos"`
+}
+
+// SLOTarget declares a pass/fail threshold for one quantile of a measurement's latency summary. Any
+// measurement that supports SLOs (see Measurement.SLOs) evaluates these through the same shape.
+type SLOTarget struct {
+	Quantile    string `json:"quantile" yaml:"quantile"`       // P50, P99, Max or Avg
+	MetricName  string `json:"metricName" yaml:"metricName"`   // e.g. "Ready", "IPAssigned", "Admitted"
+	ThresholdMs int    `json:"thresholdMs" yaml:"thresholdMs"`
+}
+
+// SvcLatencyNs is the namespace the in-cluster service latency checker Pod runs in.
+var SvcLatencyNs = &corev1.Namespace{
+	ObjectMeta: metav1.ObjectMeta{Name: "kube-burner-service-latency"},
+}
+
+// SvcLatencyChecker is the in-cluster Pod used to probe Services, Ingresses and Routes from inside
+// the cluster, the same way a real client would reach them.
+var SvcLatencyChecker = &corev1.Pod{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:      "svc-latency-checker",
+		Namespace: SvcLatencyNs.Name,
+	},
+	Spec: corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Name:    "svc-latency-checker",
+				Image:   "quay.io/cloud-bulldozer/sampleapp:latest",
+				Command: []string{"sleep", "inf"},
+			},
+		},
+	},
+}
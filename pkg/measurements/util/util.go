@@ -0,0 +1,170 @@
+// Copyright 2023 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// dialTimeout bounds a single TCP/UDP dial attempt within the overall probe timeout.
+const dialTimeout = 500 * time.Millisecond
+
+// SvcLatencyChecker probes Services, Ingresses and Routes from inside the cluster, using the
+// long-lived checker Pod deployed alongside the measurement, so probes observe the same network path
+// a real client inside the cluster would.
+type SvcLatencyChecker struct {
+	ClientSet  kubernetes.Clientset
+	RestConfig rest.Config
+	Pod        corev1.Pod
+}
+
+// NewSvcLatencyChecker returns a SvcLatencyChecker wrapping the in-cluster checker Pod.
+func NewSvcLatencyChecker(clientSet kubernetes.Clientset, restConfig rest.Config) (SvcLatencyChecker, error) {
+	pod, err := clientSet.CoreV1().Pods(types.SvcLatencyNs.Name).Get(context.TODO(), types.SvcLatencyChecker.Name, metav1.GetOptions{})
+	if err != nil {
+		return SvcLatencyChecker{}, fmt.Errorf("getting service latency checker pod: %w", err)
+	}
+	return SvcLatencyChecker{
+		ClientSet:  clientSet,
+		RestConfig: restConfig,
+		Pod:        *pod,
+	}, nil
+}
+
+// Ping repeatedly dials ip:port over TCP until a connection succeeds or timeout elapses.
+func (s *SvcLatencyChecker) Ping(ip string, port int32, timeout time.Duration) error {
+	endpoint := net.JoinHostPort(ip, strconv.Itoa(int(port)))
+	return wait.PollUntilContextTimeout(context.TODO(), 100*time.Millisecond, timeout, true, func(ctx context.Context) (bool, error) {
+		conn, err := net.DialTimeout("tcp", endpoint, dialTimeout)
+		if err != nil {
+			return false, nil
+		}
+		conn.Close()
+		return true, nil
+	})
+}
+
+// PingUDP repeatedly sends a small datagram to ip:port until one is written without error, or timeout
+// elapses. UDP is connectionless, so a successful write only confirms the datagram was sendable, not
+// that anything on the other end received or answered it.
+func (s *SvcLatencyChecker) PingUDP(ip string, port int32, timeout time.Duration) error {
+	endpoint := net.JoinHostPort(ip, strconv.Itoa(int(port)))
+	return wait.PollUntilContextTimeout(context.TODO(), 100*time.Millisecond, timeout, true, func(ctx context.Context) (bool, error) {
+		conn, err := net.DialTimeout("udp", endpoint, dialTimeout)
+		if err != nil {
+			return false, nil
+		}
+		defer conn.Close()
+		if _, err := conn.Write([]byte("kube-burner")); err != nil {
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+// PingHTTP repeatedly issues a GET against scheme://ip:port/path until a response with
+// expectedStatus is observed, or timeout elapses.
+func (s *SvcLatencyChecker) PingHTTP(ip string, port int32, scheme, path string, expectedStatus int, timeout time.Duration) error {
+	url := probeURL(scheme, net.JoinHostPort(ip, strconv.Itoa(int(port))), path)
+	client := probeHTTPClient(scheme)
+	return wait.PollUntilContextTimeout(context.TODO(), 200*time.Millisecond, timeout, true, func(ctx context.Context) (bool, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			return false, nil
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == expectedStatus, nil
+	})
+}
+
+// ProbeHTTPDetailed is like PingHTTP, but against a hostname rather than an ip:port, and it reports
+// how long DNS resolution and the TLS handshake took on the attempt that finally succeeded. The TLS
+// handshake is timed with an httptrace.ClientTrace around TLSHandshakeStart/TLSHandshakeDone, so it
+// reflects the handshake itself rather than the whole round trip (for an http probe, or one that never
+// reaches the handshake, tlsLatency stays zero). It's used by measurements (e.g. ingressLatency) that
+// need to break a single readiness probe down into its component latencies instead of a single
+// round-trip duration.
+func (s *SvcLatencyChecker) ProbeHTTPDetailed(host, path, scheme string, expectedStatus int, timeout time.Duration) (dnsLatency, tlsLatency time.Duration, err error) {
+	if scheme == "" {
+		scheme = "http"
+	}
+	url := probeURL(scheme, host, path)
+	client := probeHTTPClient(scheme)
+	err = wait.PollUntilContextTimeout(context.TODO(), 200*time.Millisecond, timeout, true, func(ctx context.Context) (bool, error) {
+		dnsStart := time.Now()
+		if _, lookupErr := net.LookupHost(host); lookupErr != nil {
+			return false, nil
+		}
+		dnsLatency = time.Since(dnsStart)
+		req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if reqErr != nil {
+			return false, reqErr
+		}
+		var tlsStart time.Time
+		tlsLatency = 0
+		trace := &httptrace.ClientTrace{
+			TLSHandshakeStart: func() { tlsStart = time.Now() },
+			TLSHandshakeDone: func(_ tls.ConnectionState, handshakeErr error) {
+				if handshakeErr == nil && !tlsStart.IsZero() {
+					tlsLatency = time.Since(tlsStart)
+				}
+			},
+		}
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+		resp, reqErr := client.Do(req)
+		if reqErr != nil {
+			return false, nil
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == expectedStatus, nil
+	})
+	return dnsLatency, tlsLatency, err
+}
+
+// probeURL joins scheme, host and path into a URL, tolerating a path with or without a leading slash.
+func probeURL(scheme, host, path string) string {
+	if path == "" {
+		path = "/"
+	} else if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, host, path)
+}
+
+// probeHTTPClient returns an http.Client suitable for probing a kube-burner workload. Workload
+// certificates are typically self-signed or cluster-internal, so TLS verification is skipped for
+// https probes; probes only care about reachability and status code, not certificate trust.
+func probeHTTPClient(scheme string) *http.Client {
+	client := &http.Client{Timeout: 5 * time.Second}
+	if scheme == "https" {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}} //nolint:gosec
+	}
+	return client
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strings"
+)
+
+// GetSubnet returns the index-th subnet of the requested prefix length carved out of base, e.g.
+// GetSubnet("10.0.0.0/8", 24, 1234) returns the 1234th /24 subnet of 10.0.0.0/8. Works for both IPv4
+// and IPv6 bases. Returns an error, instead of panicking, if index overflows the space available
+// under base at the requested prefix length.
+func GetSubnet(base string, prefix, index int) (string, error) {
+	basePrefix, err := netip.ParsePrefix(base)
+	if err != nil {
+		return "", fmt.Errorf("GetSubnet: parsing base %q: %w", base, err)
+	}
+	bits := basePrefix.Addr().BitLen()
+	if prefix < basePrefix.Bits() || prefix > bits {
+		return "", fmt.Errorf("GetSubnet: requested prefix /%d is not contained within base %s", prefix, base)
+	}
+	subnetBits := prefix - basePrefix.Bits()
+	if index < 0 || (subnetBits < 63 && uint64(index) >= uint64(1)<<uint(subnetBits)) {
+		return "", fmt.Errorf("GetSubnet: index %d overflows the %d available /%d subnets in %s", index, uint64(1)<<uint(subnetBits), prefix, base)
+	}
+	offset := new(big.Int).Lsh(big.NewInt(int64(index)), uint(bits-prefix))
+	addr := new(big.Int).Add(addrToBigInt(basePrefix.Masked().Addr()), offset)
+	subnetAddr, err := bigIntToAddr(addr, basePrefix.Addr().Is4())
+	if err != nil {
+		return "", fmt.Errorf("GetSubnet: %w", err)
+	}
+	subnetPrefix, err := subnetAddr.Prefix(prefix)
+	if err != nil {
+		return "", fmt.Errorf("GetSubnet: %w", err)
+	}
+	return subnetPrefix.String(), nil
+}
+
+// GetSubnetV6 is GetSubnet restricted to IPv6 bases, returning an error if base is an IPv4 prefix.
+func GetSubnetV6(base string, prefix, index int) (string, error) {
+	basePrefix, err := netip.ParsePrefix(base)
+	if err != nil {
+		return "", fmt.Errorf("GetSubnetV6: parsing base %q: %w", base, err)
+	}
+	if basePrefix.Addr().Is4() {
+		return "", fmt.Errorf("GetSubnetV6: %s is an IPv4 prefix, use GetSubnet instead", base)
+	}
+	return GetSubnet(base, prefix, index)
+}
+
+// GetHost returns the hostIndex-th host address within cidr. Index 0 is the network address itself,
+// matching how users typically enumerate pod/node addresses out of an allocated range.
+func GetHost(cidr string, hostIndex int) (string, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return "", fmt.Errorf("GetHost: parsing CIDR %q: %w", cidr, err)
+	}
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	if hostIndex < 0 || (hostBits < 63 && uint64(hostIndex) >= uint64(1)<<uint(hostBits)) {
+		return "", fmt.Errorf("GetHost: index %d overflows the %d available hosts in %s", hostIndex, uint64(1)<<uint(hostBits), cidr)
+	}
+	addr := new(big.Int).Add(addrToBigInt(prefix.Masked().Addr()), big.NewInt(int64(hostIndex)))
+	hostAddr, err := bigIntToAddr(addr, prefix.Addr().Is4())
+	if err != nil {
+		return "", fmt.Errorf("GetHost: %w", err)
+	}
+	return hostAddr.String(), nil
+}
+
+// SplitRange returns perIteration space-separated addresses carved out of cidrOrRange (a CIDR, e.g.
+// "10.0.0.0/24", or an explicit "start-end" address range), starting at the offset for the given
+// iteration. It replaces the string-split GetIPAddress with a CIDR/range-aware version and returns a
+// rendering error, instead of panicking, when an iteration would read past the end of the range.
+func SplitRange(cidrOrRange string, iteration, perIteration int) (string, error) {
+	start, end, err := addrRange(cidrOrRange)
+	if err != nil {
+		return "", fmt.Errorf("SplitRange: %w", err)
+	}
+	startInt := addrToBigInt(start)
+	endInt := addrToBigInt(end)
+	offset := int64(iteration) * int64(perIteration)
+	addrs := make([]string, 0, perIteration)
+	for i := 0; i < perIteration; i++ {
+		cur := new(big.Int).Add(startInt, big.NewInt(offset+int64(i)))
+		if cur.Cmp(endInt) > 0 {
+			return "", fmt.Errorf("SplitRange: %d addresses per iteration overflow range %s at iteration %d", perIteration, cidrOrRange, iteration)
+		}
+		addr, err := bigIntToAddr(cur, start.Is4())
+		if err != nil {
+			return "", fmt.Errorf("SplitRange: %w", err)
+		}
+		addrs = append(addrs, addr.String())
+	}
+	return strings.Join(addrs, " "), nil
+}
+
+// addrRange returns the first and last address of a CIDR or an explicit "start-end" range.
+func addrRange(cidrOrRange string) (netip.Addr, netip.Addr, error) {
+	if start, rest, ok := strings.Cut(cidrOrRange, "-"); ok {
+		startAddr, err := netip.ParseAddr(strings.TrimSpace(start))
+		if err != nil {
+			return netip.Addr{}, netip.Addr{}, fmt.Errorf("parsing range start %q: %w", start, err)
+		}
+		endAddr, err := netip.ParseAddr(strings.TrimSpace(rest))
+		if err != nil {
+			return netip.Addr{}, netip.Addr{}, fmt.Errorf("parsing range end %q: %w", rest, err)
+		}
+		return startAddr, endAddr, nil
+	}
+	prefix, err := netip.ParsePrefix(cidrOrRange)
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("parsing %q as a CIDR or start-end range: %w", cidrOrRange, err)
+	}
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	last := new(big.Int).Add(addrToBigInt(prefix.Masked().Addr()), new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(hostBits)), big.NewInt(1)))
+	lastAddr, err := bigIntToAddr(last, prefix.Addr().Is4())
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, err
+	}
+	return prefix.Masked().Addr(), lastAddr, nil
+}
+
+func addrToBigInt(addr netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(addr.AsSlice())
+}
+
+func bigIntToAddr(i *big.Int, is4 bool) (netip.Addr, error) {
+	size := 16
+	if is4 {
+		size = 4
+	}
+	raw := i.Bytes()
+	if len(raw) > size {
+		return netip.Addr{}, fmt.Errorf("address overflows %d-byte address space", size)
+	}
+	padded := make([]byte, size)
+	copy(padded[size-len(raw):], raw)
+	if is4 {
+		var a [4]byte
+		copy(a[:], padded)
+		return netip.AddrFrom4(a), nil
+	}
+	var a [16]byte
+	copy(a[:], padded)
+	return netip.AddrFrom16(a), nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import "testing"
+
+func TestGetSubnet(t *testing.T) {
+	tests := []struct {
+		name    string
+		base    string
+		prefix  int
+		index   int
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "carve a /24 out of a /8",
+			base:   "10.0.0.0/8",
+			prefix: 24,
+			index:  1234,
+			want:   "10.4.210.0/24",
+		},
+		{
+			name:   "carve a /64 out of an IPv6 /32",
+			base:   "2001:db8::/32",
+			prefix: 64,
+			index:  2,
+			want:   "2001:db8:0:2::/64",
+		},
+		{
+			name:    "index overflows the available subnets",
+			base:    "10.0.0.0/24",
+			prefix:  26,
+			index:   4,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSubnet(tt.base, tt.prefix, tt.index)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("GetSubnet(%q, %d, %d) = %q, want error", tt.base, tt.prefix, tt.index, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSubnet(%q, %d, %d) returned unexpected error: %v", tt.base, tt.prefix, tt.index, err)
+			}
+			if got != tt.want {
+				t.Errorf("GetSubnet(%q, %d, %d) = %q, want %q", tt.base, tt.prefix, tt.index, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitRange(t *testing.T) {
+	tests := []struct {
+		name         string
+		cidrOrRange  string
+		iteration    int
+		perIteration int
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "first iteration of a /30",
+			cidrOrRange:  "10.0.0.0/30",
+			iteration:    0,
+			perIteration: 2,
+			want:         "10.0.0.0 10.0.0.1",
+		},
+		{
+			name:         "iteration runs past the end of the range",
+			cidrOrRange:  "10.0.0.0/30",
+			iteration:    2,
+			perIteration: 2,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitRange(tt.cidrOrRange, tt.iteration, tt.perIteration)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SplitRange(%q, %d, %d) = %q, want error", tt.cidrOrRange, tt.iteration, tt.perIteration, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitRange(%q, %d, %d) returned unexpected error: %v", tt.cidrOrRange, tt.iteration, tt.perIteration, err)
+			}
+			if got != tt.want {
+				t.Errorf("SplitRange(%q, %d, %d) = %q, want %q", tt.cidrOrRange, tt.iteration, tt.perIteration, got, tt.want)
+			}
+		})
+	}
+}
@@ -16,10 +16,14 @@ package util
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
-	"net/netip"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"plugin"
 	"strings"
+	"sync"
 	"text/template"
 
 	sprig "github.com/Masterminds/sprig/v3"
@@ -34,16 +38,24 @@ const (
 	MissingKeyZero  templateOption = "missingkey=zero"
 )
 
-var funcMap = sprig.GenericFuncMap()
+// legacySubnet24Base is the implicit base kube-burner's original GetSubnet24 carved /24s out of.
+const legacySubnet24Base = "1.0.0.0/8"
 
-func init() {
-	AddRenderingFunction("Binomial", combin.Binomial)
-	AddRenderingFunction("IndexToCombination", combin.IndexToCombination)
-	funcMap["GetSubnet24"] = func(subnetIdx int) string { // TODO Document this function
-		return netip.AddrFrom4([4]byte{byte(subnetIdx>>16 + 1), byte(subnetIdx >> 8), byte(subnetIdx), 0}).String() + "/24"
-	}
+// builtinFuncMap holds kube-burner's own template helpers, added to an engine via WithKubeBurnerBuiltins.
+var builtinFuncMap = template.FuncMap{
+	"Binomial":           combin.Binomial,
+	"IndexToCombination": combin.IndexToCombination,
+	"GetSubnet":          GetSubnet,
+	"GetSubnetV6":        GetSubnetV6,
+	"GetHost":            GetHost,
+	"SplitRange":         SplitRange,
+	// GetSubnet24 is deprecated in favor of GetSubnet, which supports arbitrary bases, prefixes and IPv6.
+	"GetSubnet24": func(subnetIdx int) (string, error) {
+		log.Warn("GetSubnet24 is deprecated, use GetSubnet instead, e.g. GetSubnet \"1.0.0.0/8\" 24 <index>")
+		return GetSubnet(legacySubnet24Base, 24, subnetIdx)
+	},
 	// This function returns number of addresses requested per iteration from the list of total provided addresses
-	funcMap["GetIPAddress"] = func(Addresses string, iteration int, addressesPerIteration int) string { // TODO Move this function to kube-burner-ocp
+	"GetIPAddress": func(Addresses string, iteration int, addressesPerIteration int) string { // TODO Move this function to kube-burner-ocp
 		var retAddrs []string
 		addrSlice := strings.Split(Addresses, " ")
 		for i := 0; i < addressesPerIteration; i++ {
@@ -52,18 +64,197 @@ func init() {
 			retAddrs = append(retAddrs, addrSlice[(iteration*addressesPerIteration)+i])
 		}
 		return strings.Join(retAddrs, " ")
+	},
+}
+
+// TemplateEngine holds an isolated set of template functions. Unlike a single package-level func map
+// shared by every caller, each engine only exposes the functions it was explicitly built with, so a
+// job can't see functions registered by other packages (e.g. kube-burner-ocp) or by other jobs.
+type TemplateEngine struct {
+	funcMap template.FuncMap
+}
+
+// EngineOption configures a TemplateEngine built with NewTemplateEngine. Options are applied in order.
+type EngineOption func(*TemplateEngine)
+
+// WithSprig adds the sprig function library to the engine.
+func WithSprig() EngineOption {
+	return func(e *TemplateEngine) {
+		for name, fn := range sprig.GenericFuncMap() {
+			e.funcMap[name] = fn
+		}
+	}
+}
+
+// WithKubeBurnerBuiltins adds kube-burner's own template helpers (GetSubnet24, GetIPAddress, Binomial, ...).
+func WithKubeBurnerBuiltins() EngineOption {
+	return func(e *TemplateEngine) {
+		for name, fn := range builtinFuncMap {
+			e.funcMap[name] = fn
+		}
+	}
+}
+
+// WithFunctions merges an arbitrary set of functions into the engine, overwriting any existing entry
+// with the same name.
+func WithFunctions(functions map[string]any) EngineOption {
+	return func(e *TemplateEngine) {
+		for name, fn := range functions {
+			e.funcMap[name] = fn
+		}
 	}
 }
 
-func AddRenderingFunction(name string, function any) {
+// WithDenylist removes the given function names from the engine. Apply it after WithSprig to drop
+// functions that are dangerous in multi-tenant runs, e.g. "env", "expandenv" or "getHostByName".
+func WithDenylist(names []string) EngineOption {
+	return func(e *TemplateEngine) {
+		for _, name := range names {
+			delete(e.funcMap, name)
+		}
+	}
+}
+
+// WithPluginDir imports template functions from every plugin found in dir, so users can ship their
+// own template helpers as a binary without recompiling kube-burner. Two kinds of plugin are
+// supported:
+//   - Go plugins (*.so), loaded in-process via plugin.Open, exposing their functions through a
+//     "FuncMap" symbol of type map[string]any.
+//   - Out-of-process plugins: any other executable file in dir, invoked over a small stdio protocol
+//     in the spirit of the Terraform/Vault plugin model (see stdioPluginFunc).
+func WithPluginDir(dir string) EngineOption {
+	return func(e *TemplateEngine) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			log.Errorf("Listing template plugins in %s: %s", dir, err)
+			return
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if strings.HasSuffix(entry.Name(), ".so") {
+				loadGoPlugin(e, path)
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || info.Mode()&0o111 == 0 {
+				continue // not executable, and not a .so: not a plugin we know how to load
+			}
+			loadStdioPlugin(e, path)
+		}
+	}
+}
+
+// loadGoPlugin imports the template functions an in-process Go plugin exposes through its "FuncMap"
+// symbol (a map[string]any).
+func loadGoPlugin(e *TemplateEngine, path string) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		log.Errorf("Loading template plugin %s: %s", path, err)
+		return
+	}
+	sym, err := p.Lookup("FuncMap")
+	if err != nil {
+		log.Errorf("Template plugin %s does not export FuncMap: %s", path, err)
+		return
+	}
+	funcs, ok := sym.(*map[string]any)
+	if !ok {
+		log.Errorf("Template plugin %s: FuncMap symbol has an unexpected type", path)
+		return
+	}
+	for name, fn := range *funcs {
+		log.Debugf("Importing template function %q from Go plugin %s", name, path)
+		e.funcMap[name] = fn
+	}
+}
+
+// Flags the out-of-process plugin protocol invokes the plugin binary with.
+const (
+	stdioPluginListFlag = "--kube-burner-plugin-functions"
+	stdioPluginCallFlag = "--kube-burner-plugin-call"
+)
+
+// stdioPluginResponse is what a plugin binary writes to stdout in response to a stdioPluginCallFlag
+// invocation.
+type stdioPluginResponse struct {
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+// loadStdioPlugin discovers the functions an out-of-process plugin binary exposes by invoking it
+// with stdioPluginListFlag, which must print a JSON array of function names to stdout, and registers
+// a wrapper for each that calls back into the plugin on every template invocation.
+func loadStdioPlugin(e *TemplateEngine, path string) {
+	out, err := exec.Command(path, stdioPluginListFlag).Output()
+	if err != nil {
+		log.Errorf("Listing functions of template plugin %s: %s", path, err)
+		return
+	}
+	var names []string
+	if err := json.Unmarshal(out, &names); err != nil {
+		log.Errorf("Template plugin %s returned an invalid function list: %s", path, err)
+		return
+	}
+	for _, name := range names {
+		log.Debugf("Importing template function %q from plugin %s", name, path)
+		e.funcMap[name] = stdioPluginFunc(path, name)
+	}
+}
+
+// stdioPluginFunc returns a template function that invokes name on the plugin binary at path: its
+// arguments (stringified) are marshaled as a JSON array and written to the plugin's stdin, and the
+// plugin answers with a single JSON stdioPluginResponse line on stdout. This mirrors, at a minimal
+// scale, how Terraform/Vault plugins are invoked out-of-process, without requiring kube-burner to
+// vendor a gRPC stack just for user-supplied template helpers.
+func stdioPluginFunc(path, name string) func(args ...any) (string, error) {
+	return func(args ...any) (string, error) {
+		strArgs := make([]string, len(args))
+		for i, arg := range args {
+			strArgs[i] = fmt.Sprint(arg)
+		}
+		input, err := json.Marshal(strArgs)
+		if err != nil {
+			return "", fmt.Errorf("marshaling arguments for plugin function %s: %w", name, err)
+		}
+		cmd := exec.Command(path, stdioPluginCallFlag, name)
+		cmd.Stdin = bytes.NewReader(input)
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("calling plugin function %s: %w", name, err)
+		}
+		var resp stdioPluginResponse
+		if err := json.Unmarshal(out, &resp); err != nil {
+			return "", fmt.Errorf("decoding response from plugin function %s: %w", name, err)
+		}
+		if resp.Error != "" {
+			return "", fmt.Errorf("plugin function %s: %s", name, resp.Error)
+		}
+		return resp.Result, nil
+	}
+}
+
+// NewTemplateEngine builds a TemplateEngine from the given options, applied in order.
+func NewTemplateEngine(opts ...EngineOption) *TemplateEngine {
+	e := &TemplateEngine{funcMap: template.FuncMap{}}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// AddFunction registers a single function on this engine.
+func (e *TemplateEngine) AddFunction(name string, function any) {
 	log.Debugf("Importing template function: %s", name)
-	funcMap[name] = function
+	e.funcMap[name] = function
 }
 
-// RenderTemplate renders a go-template
-func RenderTemplate(original []byte, inputData interface{}, options templateOption) ([]byte, error) {
+// RenderTemplate renders a go-template using this engine's function map.
+func (e *TemplateEngine) RenderTemplate(original []byte, inputData interface{}, options templateOption) ([]byte, error) {
 	var rendered bytes.Buffer
-	t, err := template.New("").Option(string(options)).Funcs(funcMap).Parse(string(original))
+	t, err := template.New("").Option(string(options)).Funcs(e.funcMap).Parse(string(original))
 	if err != nil {
 		return nil, fmt.Errorf("parsing error: %s", err)
 	}
@@ -75,6 +266,36 @@ func RenderTemplate(original []byte, inputData interface{}, options templateOpti
 	return rendered.Bytes(), nil
 }
 
+var (
+	jobEnginesMu sync.Mutex
+	jobEngines   = map[string]*TemplateEngine{}
+)
+
+// EngineForJob returns the TemplateEngine associated with jobName (a Job's config.Job.Name),
+// building one from opts the first time it's requested for that name. Every later call for the same
+// jobName reuses that engine and therefore its function map, so functions added for one job (via
+// WithFunctions/WithPluginDir) never leak into another job's templates the way the single
+// package-level funcMap historically did.
+func EngineForJob(jobName string, opts ...EngineOption) *TemplateEngine {
+	jobEnginesMu.Lock()
+	defer jobEnginesMu.Unlock()
+	if e, ok := jobEngines[jobName]; ok {
+		return e
+	}
+	e := NewTemplateEngine(opts...)
+	jobEngines[jobName] = e
+	return e
+}
+
+// RenderTemplateForJob renders original using the TemplateEngine associated with jobName (see
+// EngineForJob). There is no shared, package-level engine to fall back to: a caller renders either
+// through this Job-scoped function map or through a TemplateEngine it built itself with
+// NewTemplateEngine, and never observes functions registered for a different Job.
+func RenderTemplateForJob(jobName string, original []byte, inputData interface{}, options templateOption) ([]byte, error) {
+	engine := EngineForJob(jobName, WithSprig(), WithKubeBurnerBuiltins())
+	return engine.RenderTemplate(original, inputData, options)
+}
+
 // EnvToMap returns the host environment variables as a map
 func EnvToMap() map[string]interface{} {
 	envMap := make(map[string]interface{})